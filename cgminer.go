@@ -49,6 +49,11 @@ type CGMiner struct {
 	// CGMiner might have one of two API formats - JSON or plain text.
 	// JSON is default one.
 	Transport Transport
+
+	// Pool is an optional connection pool. When set, connections are
+	// obtained from and returned to the pool instead of being dialed and
+	// closed on every call. See ConnPool and NewIdleConnPool.
+	Pool ConnPool
 }
 
 // Call sends command to cgminer API and writes result to passed response output
@@ -65,36 +70,98 @@ func (c *CGMiner) Call(cmd Command, out AbstractResponse) error {
 //
 // If command doesn't returns any response, nil "out" value should be passed.
 func (c *CGMiner) CallContext(ctx context.Context, cmd Command, out AbstractResponse) error {
-	conn, err := c.Dialer.DialContext(ctx, "tcp", c.Address)
+	conn, err := c.getConn(ctx)
 	if err != nil {
 		return ConnectError{err: err}
 	}
 
-	defer conn.Close()
-	_ = conn.SetDeadline(time.Now().Add(c.Timeout))
+	_ = conn.SetDeadline(c.deadline(ctx))
 	if err = c.Transport.SendCommand(conn, cmd); err != nil {
+		c.releaseConn(conn, false)
 		return fmt.Errorf("failed to send cgminer command: %w", err)
 	}
 
-	return c.Transport.DecodeResponse(conn, cmd, out)
+	reusable, err := c.Transport.DecodeResponse(conn, cmd, out)
+	c.releaseConn(conn, reusable && err == nil)
+	return err
 }
 
 // RawCall sends command to CGMiner API and returns raw response as slice of bytes.
 //
 // Response error check should be performed manually.
 func (c *CGMiner) RawCall(ctx context.Context, cmd Command) ([]byte, error) {
-	conn, err := c.Dialer.DialContext(ctx, "tcp", c.Address)
+	conn, err := c.getConn(ctx)
 	if err != nil {
 		return nil, ConnectError{err: err}
 	}
 
-	defer conn.Close()
-	_ = conn.SetDeadline(time.Now().Add(c.Timeout))
+	_ = conn.SetDeadline(c.deadline(ctx))
 	if err = c.Transport.SendCommand(conn, cmd); err != nil {
+		c.releaseConn(conn, false)
 		return nil, err
 	}
 
-	return readWithNullTerminator(conn)
+	// readWithNullTerminator bypasses Transport.DecodeResponse, so there is
+	// no per-call reusability signal for it; treat the connection as
+	// not reusable rather than risk handing a pool consumer a socket left
+	// in an unknown framing state.
+	out, err := readWithNullTerminator(conn)
+	c.releaseConn(conn, false)
+	return out, err
+}
+
+// getConn returns a connection to c.Address, either from c.Pool when one is
+// configured or freshly dialed via c.Dialer otherwise.
+func (c *CGMiner) getConn(ctx context.Context) (net.Conn, error) {
+	if c.Pool != nil {
+		return c.Pool.Get(ctx)
+	}
+
+	return c.Dialer.DialContext(ctx, "tcp", c.Address)
+}
+
+// releaseConn returns conn to c.Pool, or closes it directly when no pool is
+// configured. reusable must reflect whether the connection was left in a
+// state another call can safely reuse, as signaled per-call by
+// Transport.DecodeResponse.
+func (c *CGMiner) releaseConn(conn net.Conn, reusable bool) {
+	if c.Pool == nil {
+		_ = conn.Close()
+		return
+	}
+
+	if reusable {
+		c.Pool.Put(conn, nil)
+		return
+	}
+
+	c.Pool.Put(conn, errConnNotReusable)
+}
+
+// deadline returns the earliest of the context's deadline (if any) and
+// time.Now().Add(c.Timeout), so a tighter per-call bound on ctx is never
+// overridden by the client's own timeout. If c.Timeout is zero, the context
+// deadline is used as-is.
+func (c *CGMiner) deadline(ctx context.Context) time.Time {
+	return resolveDeadline(ctx, c.Timeout)
+}
+
+// resolveDeadline returns the earliest of ctx's deadline (if any) and
+// time.Now().Add(timeout), so a tighter per-call bound on ctx is never
+// overridden by a client's own timeout. If timeout is zero, ctx's deadline
+// is used as-is. Shared by CGMiner and MultiCGMiner so the two can't drift.
+func resolveDeadline(ctx context.Context, timeout time.Duration) time.Time {
+	ctxDeadline, ok := ctx.Deadline()
+	if timeout == 0 {
+		return ctxDeadline
+	}
+
+	timeoutDeadline := time.Now().Add(timeout)
+	if ok && ctxDeadline.Before(timeoutDeadline) {
+		return ctxDeadline
+	}
+
+	return timeoutDeadline
 }
 
 // NewCGMiner returns a CGMiner client with JSON API transport
@@ -0,0 +1,38 @@
+package cgminer
+
+import (
+	"context"
+	"net"
+)
+
+// fakeDialer is a test Dialer whose DialContext behavior is driven by a
+// caller-supplied function.
+type fakeDialer struct {
+	dial func(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+func (d *fakeDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	return d.dial(ctx, network, address)
+}
+
+func (d *fakeDialer) Dial(network, address string) (net.Conn, error) {
+	return d.dial(context.Background(), network, address)
+}
+
+// closeTrackingConn wraps a net.Conn and closes the closed channel the
+// first time Close is called, so tests can observe when a connection was
+// released.
+type closeTrackingConn struct {
+	net.Conn
+	closed chan struct{}
+}
+
+func (c *closeTrackingConn) Close() error {
+	err := c.Conn.Close()
+	select {
+	case <-c.closed:
+	default:
+		close(c.closed)
+	}
+	return err
+}
@@ -0,0 +1,35 @@
+package cgminer
+
+import (
+	"time"
+)
+
+// NewCGMinerWithDialer returns a CGMiner client with JSON API transport that
+// dials connections using the provided Dialer instead of the default
+// *net.Dialer.
+//
+// This is useful when connections need to be routed through a proxy (see
+// NewProxyDialer) or wrapped with custom retry/pooling behavior.
+func NewCGMinerWithDialer(address string, timeout time.Duration, d Dialer) *CGMiner {
+	return &CGMiner{
+		Address:   address,
+		Timeout:   timeout,
+		Transport: NewJSONTransport(),
+		Dialer:    d,
+	}
+}
+
+// NewCGMinerWithPool returns a CGMiner client with JSON API transport that
+// keeps connections to address alive across calls via pool instead of
+// dialing a fresh connection per call. Callers scraping many miners every
+// few seconds can use this to cut down on SYN/FIN churn; see IdleConnPool
+// for the default pool implementation. Callers should defer pool.Close() to
+// release any idle connections on shutdown.
+func NewCGMinerWithPool(address string, timeout time.Duration, pool ConnPool) *CGMiner {
+	return &CGMiner{
+		Address:   address,
+		Timeout:   timeout,
+		Transport: NewJSONTransport(),
+		Pool:      pool,
+	}
+}
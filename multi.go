@@ -0,0 +1,153 @@
+package cgminer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// DialResult describes the winning connection from a "dial-first" race
+// across multiple addresses.
+type DialResult struct {
+	// Conn is the established connection.
+	Conn net.Conn
+
+	// Address is the endpoint that won the race.
+	Address string
+}
+
+// dialErrors aggregates a connection error per dialed address.
+type dialErrors struct {
+	errs map[string]error
+}
+
+// Error implements error
+func (e *dialErrors) Error() string {
+	parts := make([]string, 0, len(e.errs))
+	for addr, err := range e.errs {
+		parts = append(parts, fmt.Sprintf("%s: %s", addr, err))
+	}
+
+	return fmt.Sprintf("all endpoints failed (%s)", strings.Join(parts, "; "))
+}
+
+// MultiCGMiner is a CGMiner client that, given several addresses for the
+// same logical miner (e.g. a primary IP, a hostname, and a management VLAN
+// address), races a dial against all of them and uses whichever connects
+// first. This masks a flaky management interface without needing an
+// external load balancer.
+type MultiCGMiner struct {
+	// Addresses is the set of candidate API endpoints (host:port).
+	Addresses []string
+
+	// Timeout is request timeout
+	Timeout time.Duration
+
+	// Dialer is network dialer
+	Dialer Dialer
+
+	// Transport is request and response decoder.
+	Transport Transport
+}
+
+// NewMultiCGMiner returns a MultiCGMiner client with JSON API transport that
+// dials all of addresses concurrently for every call.
+func NewMultiCGMiner(addresses []string, timeout time.Duration) *MultiCGMiner {
+	return &MultiCGMiner{
+		Addresses: addresses,
+		Timeout:   timeout,
+		Transport: NewJSONTransport(),
+		Dialer: &net.Dialer{
+			Timeout: timeout,
+		},
+	}
+}
+
+// dialAttempt is the outcome of dialing a single address as part of
+// dialFirst.
+type dialAttempt struct {
+	address string
+	result  DialResult
+	err     error
+}
+
+// dialFirst races a DialContext call against every address in m.Addresses
+// and returns the first successful connection. Losing dials are canceled
+// and their connections, if any arrive late, are closed promptly by
+// closeLosers so sockets are not leaked.
+func (m *MultiCGMiner) dialFirst(ctx context.Context) (DialResult, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	resCh := make(chan dialAttempt, len(m.Addresses))
+	for _, address := range m.Addresses {
+		address := address
+		go func() {
+			conn, err := m.Dialer.DialContext(ctx, "tcp", address)
+			if err != nil {
+				resCh <- dialAttempt{address: address, err: err}
+				return
+			}
+
+			resCh <- dialAttempt{address: address, result: DialResult{Conn: conn, Address: address}}
+		}()
+	}
+
+	errs := &dialErrors{errs: make(map[string]error, len(m.Addresses))}
+	for i := 0; i < len(m.Addresses); i++ {
+		a := <-resCh
+		if a.err == nil {
+			cancel()
+			go closeLosers(resCh, len(m.Addresses)-i-1)
+			return a.result, nil
+		}
+
+		errs.errs[a.address] = a.err
+	}
+
+	return DialResult{}, ConnectError{err: errs}
+}
+
+// closeLosers drains the remaining n dial attempts after a winner has been
+// picked, closing any connections that arrive late so they are not leaked.
+func closeLosers(resCh <-chan dialAttempt, n int) {
+	for i := 0; i < n; i++ {
+		if a := <-resCh; a.err == nil && a.result.Conn != nil {
+			_ = a.result.Conn.Close()
+		}
+	}
+}
+
+// CallContext races a dial against every address in m.Addresses and sends
+// cmd over whichever connection wins, writing the result to out.
+//
+// If command doesn't returns any response, nil "out" value should be passed.
+// Use CallContextResult if the caller needs to know which address won.
+func (m *MultiCGMiner) CallContext(ctx context.Context, cmd Command, out AbstractResponse) error {
+	_, err := m.CallContextResult(ctx, cmd, out)
+	return err
+}
+
+// CallContextResult behaves like CallContext but also returns the
+// DialResult describing which address won the dial race, so callers can
+// tell which management interface actually answered.
+func (m *MultiCGMiner) CallContextResult(ctx context.Context, cmd Command, out AbstractResponse) (DialResult, error) {
+	dr, err := m.dialFirst(ctx)
+	if err != nil {
+		return DialResult{}, err
+	}
+
+	conn := dr.Conn
+	defer conn.Close()
+
+	_ = conn.SetDeadline(resolveDeadline(ctx, m.Timeout))
+
+	if err = m.Transport.SendCommand(conn, cmd); err != nil {
+		return dr, fmt.Errorf("failed to send cgminer command: %w", err)
+	}
+
+	_, err = m.Transport.DecodeResponse(conn, cmd, out)
+	return dr, err
+}
@@ -0,0 +1,67 @@
+package cgminer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestMultiCGMiner_dialFirst_picksFirstSuccessAndClosesLoser(t *testing.T) {
+	fastConn, fastClosed := newTrackedPipeConn(t)
+	slowConn, slowClosed := newTrackedPipeConn(t)
+
+	d := &fakeDialer{dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+		switch address {
+		case "fast":
+			return fastConn, nil
+		case "slow":
+			time.Sleep(50 * time.Millisecond)
+			return slowConn, nil
+		default:
+			return nil, fmt.Errorf("unexpected address %q", address)
+		}
+	}}
+
+	m := &MultiCGMiner{Addresses: []string{"fast", "slow"}, Dialer: d}
+
+	res, err := m.dialFirst(context.Background())
+	if err != nil {
+		t.Fatalf("dialFirst: %v", err)
+	}
+	if res.Address != "fast" {
+		t.Fatalf("expected fast to win, got %q", res.Address)
+	}
+
+	select {
+	case <-slowClosed:
+	case <-time.After(time.Second):
+		t.Fatal("losing connection was not closed")
+	}
+
+	select {
+	case <-fastClosed:
+		t.Fatal("winning connection was closed unexpectedly")
+	default:
+	}
+}
+
+func TestMultiCGMiner_dialFirst_aggregatesErrorsWhenAllFail(t *testing.T) {
+	d := &fakeDialer{dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+		return nil, fmt.Errorf("connection refused")
+	}}
+
+	m := &MultiCGMiner{Addresses: []string{"a:4028", "b:4028"}, Dialer: d}
+
+	_, err := m.dialFirst(context.Background())
+	if err == nil {
+		t.Fatal("expected an error when all endpoints fail")
+	}
+
+	var connErr ConnectError
+	if !errors.As(err, &connErr) {
+		t.Fatalf("expected a ConnectError, got %T: %v", err, err)
+	}
+}
@@ -0,0 +1,142 @@
+package cgminer
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// errConnNotReusable is passed to ConnPool.Put to signal that the connection
+// must be closed rather than kept idle.
+var errConnNotReusable = errors.New("cgminer: connection not reusable")
+
+// ConnPool is a pool of connections to a single cgminer API endpoint, used
+// by CGMiner to keep connections alive across calls instead of dialing one
+// per call.
+type ConnPool interface {
+	// Get returns an idle connection from the pool, or dials a new one if
+	// none is available.
+	Get(ctx context.Context) (net.Conn, error)
+
+	// Put returns conn to the pool for reuse. If err is non-nil, the
+	// connection is unusable and must be closed instead of pooled.
+	Put(conn net.Conn, err error)
+
+	// Close closes every connection currently idle in the pool. It does not
+	// affect connections that are checked out at the time of the call.
+	Close() error
+}
+
+// idleConn is a pooled connection together with the time it was returned to
+// the pool, used to expire connections that have been idle for too long.
+type idleConn struct {
+	conn       net.Conn
+	returnedAt time.Time
+}
+
+// IdleConnPool is a ConnPool that keeps up to MaxIdle idle connections to a
+// single address, dialed via Dialer, and discards any connection that has
+// been idle for longer than IdleTimeout.
+//
+// This lets CGMiner coalesce bursts of polling calls through a small set of
+// reusable sockets, rather than paying for a SYN/FIN exchange on every call.
+type IdleConnPool struct {
+	// Dialer is used to establish new connections on a pool miss.
+	Dialer Dialer
+
+	// Address is the endpoint all connections in this pool are dialed to.
+	Address string
+
+	// MaxIdle is the maximum number of idle connections kept in the pool.
+	// Zero means no limit.
+	MaxIdle int
+
+	// IdleTimeout is how long a connection may sit idle before it is
+	// considered stale and closed instead of reused. Zero means idle
+	// connections never expire.
+	IdleTimeout time.Duration
+
+	mu   sync.Mutex
+	idle []idleConn
+}
+
+// NewIdleConnPool returns an IdleConnPool dialing address via d, keeping at
+// most maxIdle idle connections open for up to idleTimeout.
+func NewIdleConnPool(d Dialer, address string, maxIdle int, idleTimeout time.Duration) *IdleConnPool {
+	return &IdleConnPool{
+		Dialer:      d,
+		Address:     address,
+		MaxIdle:     maxIdle,
+		IdleTimeout: idleTimeout,
+	}
+}
+
+// Get implements ConnPool
+func (p *IdleConnPool) Get(ctx context.Context) (net.Conn, error) {
+	if conn, ok := p.takeIdle(); ok {
+		return conn, nil
+	}
+
+	return p.Dialer.DialContext(ctx, "tcp", p.Address)
+}
+
+// takeIdle pops the most recently returned, still-fresh idle connection, if
+// any, closing and discarding any stale ones it finds along the way.
+func (p *IdleConnPool) takeIdle() (net.Conn, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for len(p.idle) > 0 {
+		last := len(p.idle) - 1
+		c := p.idle[last]
+		p.idle = p.idle[:last]
+
+		if p.IdleTimeout > 0 && time.Since(c.returnedAt) > p.IdleTimeout {
+			_ = c.conn.Close()
+			continue
+		}
+
+		return c.conn, true
+	}
+
+	return nil, false
+}
+
+// Put implements ConnPool
+func (p *IdleConnPool) Put(conn net.Conn, err error) {
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	p.mu.Lock()
+	if p.MaxIdle > 0 && len(p.idle) >= p.MaxIdle {
+		p.mu.Unlock()
+		_ = conn.Close()
+		return
+	}
+
+	p.idle = append(p.idle, idleConn{conn: conn, returnedAt: time.Now()})
+	p.mu.Unlock()
+}
+
+// Close implements ConnPool. It closes every connection currently idle in
+// the pool; callers should defer it for the lifetime of the pool so idle
+// sockets aren't left open past client shutdown.
+func (p *IdleConnPool) Close() error {
+	p.mu.Lock()
+	idle := p.idle
+	p.idle = nil
+	p.mu.Unlock()
+
+	var firstErr error
+	for _, c := range idle {
+		if err := c.conn.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
@@ -0,0 +1,179 @@
+package cgminer
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+// newTrackedPipeConn returns one end of a net.Pipe wrapped so closes are
+// observable, and a channel that is closed when it is closed. The other end
+// is closed via t.Cleanup.
+func newTrackedPipeConn(t *testing.T) (net.Conn, <-chan struct{}) {
+	t.Helper()
+
+	local, remote := net.Pipe()
+	t.Cleanup(func() { _ = remote.Close() })
+
+	closed := make(chan struct{})
+	return &closeTrackingConn{Conn: local, closed: closed}, closed
+}
+
+func TestIdleConnPool_ReusesConnection(t *testing.T) {
+	calls := 0
+	d := &fakeDialer{dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+		calls++
+		conn, _ := newTrackedPipeConn(t)
+		return conn, nil
+	}}
+
+	p := NewIdleConnPool(d, "miner:4028", 2, 0)
+
+	conn, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 dial, got %d", calls)
+	}
+
+	p.Put(conn, nil)
+
+	reused, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected connection to be reused without a second dial, got %d dials", calls)
+	}
+	if reused != conn {
+		t.Fatalf("expected the same connection back from the pool")
+	}
+}
+
+func TestIdleConnPool_PutDiscardsOnError(t *testing.T) {
+	d := &fakeDialer{dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, _ := newTrackedPipeConn(t)
+		return conn, nil
+	}}
+
+	p := NewIdleConnPool(d, "miner:4028", 2, 0)
+
+	conn, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	closed := conn.(*closeTrackingConn).closed
+	p.Put(conn, errConnNotReusable)
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("non-reusable connection was not closed")
+	}
+
+	if len(p.idle) != 0 {
+		t.Fatalf("expected no idle connections, got %d", len(p.idle))
+	}
+}
+
+func TestIdleConnPool_MaxIdleCap(t *testing.T) {
+	d := &fakeDialer{dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, _ := newTrackedPipeConn(t)
+		return conn, nil
+	}}
+
+	p := NewIdleConnPool(d, "miner:4028", 1, 0)
+
+	first, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	second, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+
+	p.Put(first, nil)
+
+	secondClosed := second.(*closeTrackingConn).closed
+	p.Put(second, nil)
+
+	select {
+	case <-secondClosed:
+	case <-time.After(time.Second):
+		t.Fatal("connection exceeding MaxIdle was not closed")
+	}
+
+	if len(p.idle) != 1 {
+		t.Fatalf("expected exactly 1 idle connection, got %d", len(p.idle))
+	}
+}
+
+func TestIdleConnPool_IdleTimeoutExpiry(t *testing.T) {
+	calls := 0
+	d := &fakeDialer{dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+		calls++
+		conn, _ := newTrackedPipeConn(t)
+		return conn, nil
+	}}
+
+	p := NewIdleConnPool(d, "miner:4028", 2, time.Millisecond)
+
+	conn, err := p.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	closed := conn.(*closeTrackingConn).closed
+	p.Put(conn, nil)
+
+	time.Sleep(10 * time.Millisecond)
+
+	if _, err := p.Get(context.Background()); err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected expired idle connection to be discarded and a new one dialed, got %d dials", calls)
+	}
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("expired idle connection was not closed")
+	}
+}
+
+func TestIdleConnPool_Close(t *testing.T) {
+	d := &fakeDialer{dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+		conn, _ := newTrackedPipeConn(t)
+		return conn, nil
+	}}
+
+	p := NewIdleConnPool(d, "miner:4028", 2, 0)
+
+	a, _ := p.Get(context.Background())
+	b, _ := p.Get(context.Background())
+	p.Put(a, nil)
+	p.Put(b, nil)
+
+	aClosed := a.(*closeTrackingConn).closed
+	bClosed := b.(*closeTrackingConn).closed
+
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	for _, ch := range []<-chan struct{}{aClosed, bClosed} {
+		select {
+		case <-ch:
+		case <-time.After(time.Second):
+			t.Fatal("idle connection was not closed by Close")
+		}
+	}
+
+	if len(p.idle) != 0 {
+		t.Fatalf("expected pool to be empty after Close, got %d idle conns", len(p.idle))
+	}
+}
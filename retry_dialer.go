@@ -0,0 +1,140 @@
+package cgminer
+
+import (
+	"context"
+	"errors"
+	"net"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how RetryDialer retries a failed dial.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retry attempts after the initial
+	// dial. Zero means retry indefinitely until ctx is done.
+	MaxRetries int
+
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between retries. If zero, InitialBackoff is
+	// used for every retry instead of growing exponentially.
+	MaxBackoff time.Duration
+}
+
+// minBackoff is the floor applied to RetryPolicy's InitialBackoff, so a
+// zero or misconfigured InitialBackoff can't turn retries into a tight
+// connect spin against the miner.
+const minBackoff = 10 * time.Millisecond
+
+// backoff returns the delay before the given retry attempt (0-indexed).
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	initial := p.InitialBackoff
+	if initial < minBackoff {
+		initial = minBackoff
+	}
+
+	if p.MaxBackoff == 0 {
+		return initial
+	}
+
+	d := initial
+	for i := 0; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+
+	if d > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+
+	return d
+}
+
+// RetryDialer wraps a Dialer and retries transient connect errors (e.g.
+// connection refused, ENOENT for unix sockets) with a backoff, which helps
+// with ASIC firmwares (Antminer, Whatsminer) that briefly drop their API
+// socket during pool reconfiguration and auto-tuning.
+type RetryDialer struct {
+	inner  Dialer
+	policy RetryPolicy
+}
+
+// NewRetryDialer returns a Dialer that retries inner according to policy on
+// transient connect errors, until success, ctx is canceled, or the retry
+// budget is exhausted.
+func NewRetryDialer(inner Dialer, policy RetryPolicy) Dialer {
+	return &RetryDialer{inner: inner, policy: policy}
+}
+
+// Dial implements Dialer
+func (d *RetryDialer) Dial(network, address string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, address)
+}
+
+// DialContext implements Dialer
+func (d *RetryDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		conn, err := d.dialOnce(ctx, network, address)
+		if err == nil {
+			return conn, nil
+		}
+
+		lastErr = err
+		if ctx.Err() != nil || !isTransientDialError(err) {
+			return nil, lastErr
+		}
+
+		if d.policy.MaxRetries > 0 && attempt >= d.policy.MaxRetries {
+			return nil, lastErr
+		}
+
+		timer := time.NewTimer(d.policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// dialOnce performs a single dial attempt, racing it against ctx so the
+// goroutine driving the attempt exits promptly when ctx is done. A
+// connection that arrives after ctx is already done is closed rather than
+// returned, so it isn't leaked.
+func (d *RetryDialer) dialOnce(ctx context.Context, network, address string) (net.Conn, error) {
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+
+	resCh := make(chan result, 1)
+	go func() {
+		conn, err := d.inner.DialContext(ctx, network, address)
+		resCh <- result{conn, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		go func() {
+			if r := <-resCh; r.conn != nil {
+				_ = r.conn.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	case r := <-resCh:
+		return r.conn, r.err
+	}
+}
+
+// isTransientDialError reports whether err is a dial error worth retrying,
+// such as connection refused or ENOENT on a unix socket.
+func isTransientDialError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ENOENT)
+}
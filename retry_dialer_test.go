@@ -0,0 +1,109 @@
+package cgminer
+
+import (
+	"context"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_backoff_enforcesMinimum(t *testing.T) {
+	p := RetryPolicy{MaxBackoff: time.Second}
+
+	if got := p.backoff(0); got < minBackoff {
+		t.Fatalf("expected at least minBackoff with InitialBackoff=0, got %s", got)
+	}
+}
+
+func TestRetryDialer_DialContext_retriesTransientErrorThenSucceeds(t *testing.T) {
+	attempts := 0
+	conn, _ := newTrackedPipeConn(t)
+
+	d := &fakeDialer{dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+		attempts++
+		if attempts < 3 {
+			return nil, syscall.ECONNREFUSED
+		}
+		return conn, nil
+	}}
+
+	rd := NewRetryDialer(d, RetryPolicy{InitialBackoff: time.Millisecond})
+
+	got, err := rd.DialContext(context.Background(), "tcp", "miner:4028")
+	if err != nil {
+		t.Fatalf("DialContext: %v", err)
+	}
+	if got != conn {
+		t.Fatalf("expected the eventually-successful connection to be returned")
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryDialer_DialContext_stopsOnNonTransientError(t *testing.T) {
+	attempts := 0
+	d := &fakeDialer{dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+		attempts++
+		return nil, net.InvalidAddrError("bogus address")
+	}}
+
+	rd := NewRetryDialer(d, RetryPolicy{InitialBackoff: time.Millisecond})
+
+	if _, err := rd.DialContext(context.Background(), "tcp", "miner:4028"); err == nil {
+		t.Fatal("expected an error")
+	}
+	if attempts != 1 {
+		t.Fatalf("expected no retries for a non-transient error, got %d attempts", attempts)
+	}
+}
+
+func TestRetryDialer_DialContext_exitsPromptlyOnContextDone(t *testing.T) {
+	d := &fakeDialer{dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+		return nil, syscall.ECONNREFUSED
+	}}
+
+	rd := NewRetryDialer(d, RetryPolicy{InitialBackoff: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := rd.DialContext(ctx, "tcp", "miner:4028")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("DialContext took %s to return after context deadline, want well under a second", elapsed)
+	}
+}
+
+func TestRetryDialer_DialContext_closesLateConnAfterCancel(t *testing.T) {
+	conn, closed := newTrackedPipeConn(t)
+	dialStarted := make(chan struct{})
+
+	d := &fakeDialer{dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+		close(dialStarted)
+		time.Sleep(50 * time.Millisecond)
+		return conn, nil
+	}}
+
+	rd := NewRetryDialer(d, RetryPolicy{InitialBackoff: time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-dialStarted
+		cancel()
+	}()
+
+	if _, err := rd.DialContext(ctx, "tcp", "miner:4028"); err == nil {
+		t.Fatal("expected an error after cancellation")
+	}
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatal("connection that completed after cancellation was not closed")
+	}
+}